@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// claimsKey is the context key under which authMiddleware stashes a
+// request's Claims. Unexported so callers must go through
+// ClaimsFromContext.
+type claimsKey struct{}
+
+// Claims are the custom JWT claims issued on login, identifying the
+// authenticated user and their role.
+type Claims struct {
+	UserID string `json:"uid"`
+	Role   string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// jwtSecret returns the HMAC signing key from JWT_SECRET. There is no
+// built-in fallback: a committed default would let anyone forge an
+// admin token the moment an operator forgets to set the env var, so we
+// fail closed instead.
+func jwtSecret() []byte {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		log.Fatal("JWT_SECRET must be set; refusing to start with no signing key")
+	}
+	return []byte(secret)
+}
+
+func jwtTTL() time.Duration {
+	if raw := os.Getenv("JWT_TTL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			return d
+		}
+	}
+	return 24 * time.Hour
+}
+
+// newToken signs a JWT for userID/role, expiring after jwtTTL.
+func newToken(userID, role string) (string, error) {
+	claims := Claims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(jwtTTL())),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret())
+}
+
+// parseToken verifies raw against jwtSecret and returns its Claims.
+func parseToken(raw string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtSecret(), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+// ClaimsFromContext returns the Claims stashed by authMiddleware, or nil
+// if the request carried no valid bearer token.
+func ClaimsFromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+// authMiddleware parses the Authorization: Bearer header, verifies the
+// token, and injects its Claims into the request context. It never
+// rejects a request by itself; handlers that require authentication wrap
+// with requireAuth as well.
+func authMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || raw == "" {
+			next(w, r)
+			return
+		}
+		claims, err := parseToken(raw)
+		if err != nil {
+			next(w, r)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+	}
+}
+
+// requireAuth rejects the request with 401 unless authMiddleware found
+// valid Claims.
+func requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ClaimsFromContext(r.Context()) == nil {
+			unauthorized(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireRole rejects the request with 403 unless the authenticated
+// caller's Claims.Role matches role. Must run after requireAuth so
+// Claims is guaranteed non-nil.
+func requireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims := ClaimsFromContext(r.Context())
+		if claims == nil || claims.Role != role {
+			forbidden(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// protect is authMiddleware, requireAuth, and requireRole("admin")
+// composed, for routes that must run as an authenticated admin caller.
+func protect(next http.HandlerFunc) http.HandlerFunc {
+	return authMiddleware(requireAuth(requireRole("admin", next)))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewTokenAndParseTokenRoundTrip(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	token, err := newToken("u1", "admin")
+	if err != nil {
+		t.Fatalf("newToken returned error: %v", err)
+	}
+
+	claims, err := parseToken(token)
+	if err != nil {
+		t.Fatalf("parseToken returned error: %v", err)
+	}
+	if claims.UserID != "u1" || claims.Role != "admin" {
+		t.Errorf("got claims %+v, want UserID=u1 Role=admin", claims)
+	}
+}
+
+func TestParseTokenRejectsBadSignature(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	token, err := newToken("u1", "admin")
+	if err != nil {
+		t.Fatalf("newToken returned error: %v", err)
+	}
+
+	t.Setenv("JWT_SECRET", "a-different-secret")
+	if _, err := parseToken(token); err == nil {
+		t.Error("expected parseToken to reject a token signed with a different secret")
+	}
+}
+
+func TestRequireRoleRejectsWrongRole(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	called := false
+	next := func(w http.ResponseWriter, r *http.Request) { called = true }
+	handler := requireRole("admin", next)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req = req.WithContext(context.WithValue(req.Context(), claimsKey{}, &Claims{UserID: "u1", Role: "user"}))
+	rec := httptest.NewRecorder()
+
+	handler(rec, req)
+
+	if called {
+		t.Error("next should not be called for a non-admin role")
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireRoleRejectsMissingClaims(t *testing.T) {
+	called := false
+	next := func(w http.ResponseWriter, r *http.Request) { called = true }
+	handler := requireRole("admin", next)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	rec := httptest.NewRecorder()
+
+	handler(rec, req)
+
+	if called {
+		t.Error("next should not be called with no claims in context")
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireRoleAllowsMatchingRole(t *testing.T) {
+	called := false
+	next := func(w http.ResponseWriter, r *http.Request) { called = true }
+	handler := requireRole("admin", next)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req = req.WithContext(context.WithValue(req.Context(), claimsKey{}, &Claims{UserID: "u1", Role: "admin"}))
+	rec := httptest.NewRecorder()
+
+	handler(rec, req)
+
+	if !called {
+		t.Error("next should be called for a matching admin role")
+	}
+}
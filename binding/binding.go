@@ -0,0 +1,128 @@
+// Package binding decodes request bodies and encodes responses in
+// whatever wire format the client asked for, mirroring what echo's
+// DefaultBinder does so handlers don't need to special-case the
+// Content-Type/Accept headers themselves.
+package binding
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// ErrEmptyBody is returned by Decode when a mutating request carries no
+// body at all.
+var ErrEmptyBody = errors.New("Request body can't be empty")
+
+// Decode reads r's body into v, picking a decoder from the Content-Type
+// header. application/json, application/xml, text/xml,
+// application/x-www-form-urlencoded, and multipart/form-data are
+// supported; anything else (or a missing header) falls back to JSON.
+func Decode(r *http.Request, v interface{}) error {
+	if r.ContentLength == 0 {
+		return ErrEmptyBody
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "application/json"
+	}
+
+	switch mediaType {
+	case "application/xml", "text/xml":
+		return xml.NewDecoder(r.Body).Decode(v)
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return err
+		}
+		return decodeValues(r.Form, v)
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return err
+		}
+		return decodeValues(url.Values(r.MultipartForm.Value), v)
+	default:
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+}
+
+// Encode marshals v in whatever format r's Accept header asks for
+// (application/json, application/xml, text/xml; default JSON), then
+// writes status and the encoded body to w.
+func Encode(w http.ResponseWriter, r *http.Request, status int, v interface{}) error {
+	mediaType := acceptedType(r)
+
+	var body []byte
+	var err error
+	switch mediaType {
+	case "application/xml", "text/xml":
+		body, err = xml.Marshal(v)
+	default:
+		body, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("content-type", mediaType)
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+	return err
+}
+
+func acceptedType(r *http.Request) string {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "application/xml", "text/xml", "application/json":
+			return mediaType
+		}
+	}
+	return "application/json"
+}
+
+// decodeValues copies form values into v's fields by matching each
+// field's json tag (falling back to its lowercased name). It handles
+// both string fields and *string fields (the latter for partial-update
+// payloads like the PATCH /users/:id patch struct), which covers the
+// request/response payloads this API deals in.
+func decodeValues(values url.Values, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("binding: destination must be a pointer to a struct")
+	}
+
+	elem := rv.Elem()
+	t := elem.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = strings.ToLower(field.Name)
+		}
+		val := values.Get(tag)
+		if val == "" {
+			continue
+		}
+
+		fieldVal := elem.Field(i)
+		switch {
+		case fieldVal.Kind() == reflect.String:
+			fieldVal.SetString(val)
+		case fieldVal.Kind() == reflect.Ptr && fieldVal.Type().Elem().Kind() == reflect.String:
+			fieldVal.Set(reflect.ValueOf(&val))
+		}
+	}
+	return nil
+}
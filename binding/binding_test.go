@@ -0,0 +1,129 @@
+package binding
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testPayload struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Secret string `json:"-" xml:"-"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	body := strings.NewReader(`{"id":"1","name":"Ada"}`)
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	var p testPayload
+	if err := Decode(req, &p); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if p.ID != "1" || p.Name != "Ada" {
+		t.Errorf("got %+v, want id=1 name=Ada", p)
+	}
+}
+
+func TestDecodeXML(t *testing.T) {
+	body := strings.NewReader(`<testPayload><ID>1</ID><Name>Ada</Name></testPayload>`)
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", "application/xml")
+
+	var p testPayload
+	if err := Decode(req, &p); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if p.ID != "1" || p.Name != "Ada" {
+		t.Errorf("got %+v, want id=1 name=Ada", p)
+	}
+}
+
+func TestDecodeFormURLEncoded(t *testing.T) {
+	body := strings.NewReader("id=1&name=Ada")
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var p testPayload
+	if err := Decode(req, &p); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if p.ID != "1" || p.Name != "Ada" {
+		t.Errorf("got %+v, want id=1 name=Ada", p)
+	}
+}
+
+func TestDecodeFormURLEncodedSkipsJSONDashTag(t *testing.T) {
+	body := strings.NewReader("id=1&name=Ada&secret=hunter2")
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var p testPayload
+	if err := Decode(req, &p); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if p.Secret != "" {
+		t.Errorf("Secret should not be bindable from form data, got %q", p.Secret)
+	}
+}
+
+func TestDecodeFormURLEncodedPointerField(t *testing.T) {
+	body := strings.NewReader("name=Changed")
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	patch := struct {
+		Name *string `json:"name"`
+	}{}
+	if err := Decode(req, &patch); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if patch.Name == nil || *patch.Name != "Changed" {
+		t.Errorf("got %+v, want Name=Changed", patch)
+	}
+}
+
+func TestDecodeEmptyBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = 0
+
+	var p testPayload
+	if err := Decode(req, &p); err != ErrEmptyBody {
+		t.Errorf("got err %v, want ErrEmptyBody", err)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/json")
+	rec := httptest.NewRecorder()
+
+	if err := Encode(rec, req, http.StatusOK, testPayload{ID: "1", Name: "Ada", Secret: "hunter2"}); err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	if ct := rec.Header().Get("content-type"); ct != "application/json" {
+		t.Errorf("got content-type %q, want application/json", ct)
+	}
+	if got := rec.Body.String(); strings.Contains(got, "hunter2") {
+		t.Errorf("Secret leaked into JSON response: %s", got)
+	}
+}
+
+func TestEncodeXMLOmitsJSONDashTaggedField(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/xml")
+	rec := httptest.NewRecorder()
+
+	if err := Encode(rec, req, http.StatusOK, testPayload{ID: "1", Name: "Ada", Secret: "hunter2"}); err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	if ct := rec.Header().Get("content-type"); ct != "application/xml" {
+		t.Errorf("got content-type %q, want application/xml", ct)
+	}
+	if got := rec.Body.String(); strings.Contains(got, "hunter2") {
+		t.Errorf("Secret leaked into XML response: %s", got)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CORSConfig lists the origins, methods, and headers allowed for
+// cross-origin requests. The zero value denies every cross-origin
+// request.
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+	AllowedMethods []string `yaml:"allowed_methods"`
+	AllowedHeaders []string `yaml:"allowed_headers"`
+}
+
+// loadCORSConfig builds a CORSConfig from the YAML file named by
+// CORS_CONFIG_FILE, falling back to the CORS_ALLOWED_ORIGINS,
+// CORS_ALLOWED_METHODS, and CORS_ALLOWED_HEADERS env vars
+// (comma-separated). With none of those set, it denies all cross-origin
+// requests -- operators have to opt specific origins in.
+func loadCORSConfig() (CORSConfig, error) {
+	if path := os.Getenv("CORS_CONFIG_FILE"); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return CORSConfig{}, err
+		}
+		cfg := CORSConfig{}
+		if err := yaml.Unmarshal(raw, &cfg); err != nil {
+			return CORSConfig{}, err
+		}
+		return cfg, nil
+	}
+
+	return CORSConfig{
+		AllowedOrigins: splitCSV(os.Getenv("CORS_ALLOWED_ORIGINS")),
+		AllowedMethods: splitCSV(os.Getenv("CORS_ALLOWED_METHODS")),
+		AllowedHeaders: splitCSV(os.Getenv("CORS_ALLOWED_HEADERS")),
+	}, nil
+}
+
+func splitCSV(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func (c CORSConfig) allowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// corsMiddleware denies cross-origin requests by default. For an Origin
+// present in cfg.AllowedOrigins it sets the Access-Control-Allow-*
+// headers and answers OPTIONS preflights directly; every other request
+// passes through to next untouched.
+func corsMiddleware(cfg CORSConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !cfg.allowsOrigin(origin) {
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Vary", "Origin")
+		if len(cfg.AllowedMethods) > 0 {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+		}
+		if len(cfg.AllowedHeaders) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+		}
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
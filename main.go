@@ -1,119 +1,230 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
-	"regexp"
-	"sync"
-)
+	"os"
+
+	"golang.org/x/crypto/bcrypt"
 
-var (
-	listUsersRe  = regexp.MustCompile(`^\/users[\/]*$`)
-	getUserRe    = regexp.MustCompile(`^\/users\/(\d+)$`)
-	createUserRe = regexp.MustCompile(`^\/users[\/]*$`)
+	"github.com/fikriibrahimahid/gocafe-rest-api/binding"
+	"github.com/fikriibrahimahid/gocafe-rest-api/users"
 )
 
-type user struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+// adminSeedKey is the memory-store map key reserved for the bootstrap
+// admin account, distinct from any key an operator's USERS_SEED_FILE
+// might use.
+const adminSeedKey = "__admin__"
+
+type userHandler struct {
+	store users.Store
 }
 
-type datastore struct {
-	m map[string]user
-	*sync.RWMutex
+func (h *userHandler) List(w http.ResponseWriter, r *http.Request) {
+	list, err := h.store.List(r.Context())
+	if err != nil {
+		internalServerError(w, r)
+		return
+	}
+	if err := binding.Encode(w, r, http.StatusOK, list); err != nil {
+		internalServerError(w, r)
+		return
+	}
 }
 
-type userHandler struct {
-	store *datastore
+func (h *userHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id := ParamsFromContext(r.Context()).ByName("id")
+	u, err := h.store.Get(r.Context(), id)
+	if err == users.ErrNotFound {
+		notFound(w, r)
+		return
+	}
+	if err != nil {
+		internalServerError(w, r)
+		return
+	}
+	if err := binding.Encode(w, r, http.StatusOK, u); err != nil {
+		internalServerError(w, r)
+		return
+	}
 }
 
-func (h *userHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("content-type", "application/json")
-	switch {
-	case r.Method == http.MethodGet && listUsersRe.MatchString(r.URL.Path):
-		h.List(w, r)
+func (h *userHandler) Create(w http.ResponseWriter, r *http.Request) {
+	u := users.User{}
+	if err := binding.Decode(r, &u); err == binding.ErrEmptyBody {
+		emptyBody(w, r)
 		return
-	case r.Method == http.MethodGet && getUserRe.MatchString(r.URL.Path):
-		h.Get(w, r)
+	} else if err != nil {
+		badRequest(w, r)
 		return
-	case r.Method == http.MethodPost && createUserRe.MatchString(r.URL.Path):
-		h.Create(w, r)
+	}
+	if err := h.store.Add(r.Context(), u); err != nil {
+		internalServerError(w, r)
 		return
-	default:
-		notFound(w, r)
+	}
+	if err := binding.Encode(w, r, http.StatusOK, u); err != nil {
+		internalServerError(w, r)
 		return
 	}
 }
 
-func (h *userHandler) List(w http.ResponseWriter, r *http.Request) {
-	users := make([]user, 0, len(h.store.m))
-	h.store.RLock()
-	for _, u := range h.store.m {
-		users = append(users, u)
+func (h *userHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := ParamsFromContext(r.Context()).ByName("id")
+
+	u := users.User{}
+	if err := binding.Decode(r, &u); err == binding.ErrEmptyBody {
+		emptyBody(w, r)
+		return
+	} else if err != nil {
+		badRequest(w, r)
+		return
 	}
-	h.store.RUnlock()
-	jsonBytes, err := json.Marshal(users)
-	if err != nil {
+
+	if err := h.store.Update(r.Context(), id, u); err == users.ErrNotFound {
+		notFound(w, r)
+		return
+	} else if err != nil {
+		internalServerError(w, r)
+		return
+	}
+
+	if err := binding.Encode(w, r, http.StatusOK, u); err != nil {
 		internalServerError(w, r)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonBytes)
 }
 
-func (h *userHandler) Get(w http.ResponseWriter, r *http.Request) {
-	matches := getUserRe.FindStringSubmatch(r.URL.Path)
-	if len(matches) < 2 {
+func (h *userHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id := ParamsFromContext(r.Context()).ByName("id")
+
+	patch := struct {
+		ID   *string `json:"id"`
+		Name *string `json:"name"`
+	}{}
+	if err := binding.Decode(r, &patch); err == binding.ErrEmptyBody {
+		emptyBody(w, r)
+		return
+	} else if err != nil {
+		badRequest(w, r)
+		return
+	}
+
+	u, err := h.store.Get(r.Context(), id)
+	if err == users.ErrNotFound {
 		notFound(w, r)
+		return
+	} else if err != nil {
+		internalServerError(w, r)
+		return
+	}
+
+	if patch.ID != nil {
+		u.ID = *patch.ID
+	}
+	if patch.Name != nil {
+		u.Name = *patch.Name
+	}
+
+	if err := h.store.Update(r.Context(), id, u); err != nil {
+		internalServerError(w, r)
+		return
 	}
-	h.store.RLock()
-	user, ok := h.store.m[matches[1]]
-	h.store.RUnlock()
-	if !ok {
+
+	if err := binding.Encode(w, r, http.StatusOK, u); err != nil {
+		internalServerError(w, r)
+		return
+	}
+}
+
+func (h *userHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := ParamsFromContext(r.Context()).ByName("id")
+
+	u, err := h.store.Delete(r.Context(), id)
+	if err == users.ErrNotFound {
 		notFound(w, r)
 		return
+	} else if err != nil {
+		internalServerError(w, r)
+		return
 	}
-	jsonBytes, err := json.Marshal(user)
-	if err != nil {
+
+	if err := binding.Encode(w, r, http.StatusOK, u); err != nil {
 		internalServerError(w, r)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonBytes)
 }
 
-func (h *userHandler) Create(w http.ResponseWriter, r *http.Request) {
-	u := user{}
-	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+func (h *userHandler) Login(w http.ResponseWriter, r *http.Request) {
+	creds := users.Credentials{}
+	if err := binding.Decode(r, &creds); err == binding.ErrEmptyBody {
+		emptyBody(w, r)
+		return
+	} else if err != nil {
 		badRequest(w, r)
 		return
 	}
-	h.store.Lock()
-	h.store.m[u.ID] = u
-	h.store.Unlock()
-	jsonBytes, err := json.Marshal(u)
+
+	u, err := h.store.FindByCredentials(r.Context(), creds)
+	if err == users.ErrInvalidCredentials {
+		unauthorized(w, r)
+		return
+	}
 	if err != nil {
 		internalServerError(w, r)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(jsonBytes)
+
+	token, err := newToken(u.ID, u.Role)
+	if err != nil {
+		internalServerError(w, r)
+		return
+	}
+
+	if err := binding.Encode(w, r, http.StatusOK, struct {
+		Token string `json:"token"`
+	}{Token: token}); err != nil {
+		internalServerError(w, r)
+		return
+	}
+}
+
+// writeError sets the JSON content type, writes code, and emits msg in
+// the API's error shape. badRequest/notFound/internalServerError/etc.
+// are thin wrappers around it so handlers keep a single call site per
+// error case.
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(code)
+	w.Write([]byte(`{"error" : "` + msg + `"}`))
 }
 
 func badRequest(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusBadRequest)
-	w.Write([]byte(`{"error" : "bad request"}`))
+	writeError(w, http.StatusBadRequest, "bad request")
 }
 
 func notFound(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotFound)
-	w.Write([]byte(`{"error" : "not found"}`))
+	writeError(w, http.StatusNotFound, "not found")
 }
 
 func internalServerError(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusInternalServerError)
-	w.Write([]byte(`{"error" : "interval server error"}`))
+	writeError(w, http.StatusInternalServerError, "internal server error")
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusUnauthorized, "unauthorized")
+}
+
+func forbidden(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusForbidden, "forbidden")
+}
+
+func emptyBody(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusBadRequest, "Request body can't be empty")
 }
 
 // .
@@ -122,32 +233,102 @@ func internalServerError(w http.ResponseWriter, r *http.Request) {
 // .
 // .
 
+// newStore selects the Store backend from the STORAGE env var
+// ("memory" or "postgres"), defaulting to memory.
+func newStore(ctx context.Context) (users.Store, error) {
+	switch os.Getenv("STORAGE") {
+	case "postgres":
+		return users.NewPostgresStore(ctx)
+	default:
+		seed, err := loadSeedUsers()
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := seed[adminSeedKey]; exists {
+			return nil, fmt.Errorf("newStore: USERS_SEED_FILE must not define reserved key %q", adminSeedKey)
+		}
+
+		adminPassword := os.Getenv("ADMIN_PASSWORD")
+		if adminPassword == "" {
+			adminPassword, err = randomPassword()
+			if err != nil {
+				return nil, err
+			}
+			logger.Warn("ADMIN_PASSWORD not set, generated a random admin password for this run", "password", adminPassword)
+		}
+
+		adminHash, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		seed[adminSeedKey] = users.User{ID: "ADMIN001", Name: "Admin", Email: "admin@gocafe.dev", Role: "admin", PasswordHash: string(adminHash)}
+
+		return users.NewMemoryStore(seed), nil
+	}
+}
+
+// randomPassword returns a URL-safe, base64-encoded random string
+// suitable for a one-off generated admin password.
+func randomPassword() (string, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// loadSeedUsers reads optional extra demo users from the JSON file named
+// by USERS_SEED_FILE (a map[string]users.User keyed the same way the
+// store is). With the env var unset it returns an empty set -- the
+// memory store no longer ships with a hard-coded roster baked into the
+// binary.
+func loadSeedUsers() (map[string]users.User, error) {
+	path := os.Getenv("USERS_SEED_FILE")
+	if path == "" {
+		return map[string]users.User{}, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	seed := map[string]users.User{}
+	if err := json.Unmarshal(raw, &seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
 func main() {
-	mux := http.NewServeMux()
-	userH := &userHandler{
-		store: &datastore{
-			m: map[string]user{
-				"1":  {ID: "MCI001", Name: "Kevin De Bruyne"},
-				"2":  {ID: "MCI002", Name: "Bernardo Silva"},
-				"3":  {ID: "MCI003", Name: "Erling Braut Haaland"},
-				"4":  {ID: "MCI004", Name: "Ederson Moraes"},
-				"5":  {ID: "MCI005", Name: "Jack Grealish"},
-				"6":  {ID: "MCI006", Name: "Kyle Walker"},
-				"7":  {ID: "MCI007", Name: "Joao Cancelo"},
-				"8":  {ID: "MCI008", Name: "Ruben Dias"},
-				"9":  {ID: "MCI009", Name: "Aymeric Laporte"},
-				"10": {ID: "MCI010", Name: "John Stones"},
-				"11": {ID: "MCI011", Name: "Manuel Akanji"},
-				"12": {ID: "MCI012", Name: "Ilkay Gundogan"},
-				"13": {ID: "MCI013", Name: "Phil Foden"},
-				"14": {ID: "MCI014", Name: "Riyad Mahrez"},
-			},
-			RWMutex: &sync.RWMutex{},
-		},
-	}
-	mux.Handle("/users/", userH)
-	mux.Handle("/users", userH)
-	if err := http.ListenAndServe(":8080", mux); err != nil {
+	jwtSecret() // fail fast at startup if JWT_SECRET isn't set
+
+	store, err := newStore(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	userH := &userHandler{store: store}
+
+	router := NewRouter()
+	router.POST("/login", userH.Login)
+	router.GET("/users", userH.List)
+	router.GET("/users/:id", userH.Get)
+	router.POST("/users", protect(userH.Create))
+	router.PUT("/users/:id", protect(userH.Update))
+	router.PATCH("/users/:id", protect(userH.Patch))
+	router.DELETE("/users/:id", protect(userH.Delete))
+
+	corsCfg, err := loadCORSConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var handler http.Handler = router
+	handler = corsMiddleware(corsCfg, handler)
+	handler = recoveryMiddleware(handler)
+	handler = loggingMiddleware(handler)
+	handler = requestIDMiddleware(handler)
+
+	if err := http.ListenAndServe(":8080", handler); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// requestIDKey is the context key under which requestIDMiddleware stores
+// a request's id.
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request id assigned by
+// requestIDMiddleware, or "" if the request wasn't routed through it.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDMiddleware assigns each request a UUID, echoes it in the
+// X-Request-Id response header, and stashes it in the request context
+// for loggingMiddleware, recoveryMiddleware, and handlers to read.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := uuid.NewString()
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// statusWriter records the status code and byte count a handler writes,
+// defaulting the status to 200 if WriteHeader is never called.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	n, err := sw.ResponseWriter.Write(b)
+	sw.bytes += n
+	return n, err
+}
+
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// loggingMiddleware logs method, path, status, bytes written, duration,
+// and request id for every request via log/slog in JSON.
+func loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+		start := time.Now()
+		next.ServeHTTP(sw, r)
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", sw.status,
+			"bytes", sw.bytes,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"request_id", RequestIDFromContext(r.Context()),
+		)
+	})
+}
+
+// recoveryMiddleware recovers from panics inside handlers, logs the
+// stack alongside the request id, and responds with the same JSON 500
+// shape as internalServerError.
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				logger.Error("panic recovered",
+					"request_id", RequestIDFromContext(r.Context()),
+					"panic", rec,
+					"stack", string(debug.Stack()),
+				)
+				internalServerError(w, r)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// paramsKey is the context key under which a request's route Params are
+// stored. It is unexported so only this file can set it, forcing callers
+// through ParamsFromContext.
+type paramsKey struct{}
+
+// Param is a single named path parameter captured during routing, e.g.
+// {Key: "id", Value: "42"} for a route registered as "/users/:id".
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params is the set of named parameters matched for a request.
+type Params []Param
+
+// ByName returns the value of the named parameter, or "" if it wasn't
+// captured by the matched route.
+func (p Params) ByName(name string) string {
+	for _, param := range p {
+		if param.Key == name {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// ParamsFromContext returns the Params stashed in ctx by the Router, or
+// nil if the request wasn't routed through one.
+func ParamsFromContext(ctx context.Context) Params {
+	params, _ := ctx.Value(paramsKey{}).(Params)
+	return params
+}
+
+// node is one segment of a per-method routing trie. A node with isParam
+// set matches any path segment and captures it under its own part name
+// (with the leading ':' trimmed).
+type node struct {
+	part     string
+	isParam  bool
+	children map[string]*node
+	handler  http.HandlerFunc
+}
+
+func newNode(part string) *node {
+	return &node{part: part, children: make(map[string]*node)}
+}
+
+func (n *node) insert(segments []string, handler http.HandlerFunc) {
+	cur := n
+	for _, segment := range segments {
+		key := segment
+		isParam := strings.HasPrefix(segment, ":")
+		if isParam {
+			key = ":"
+		}
+		child, ok := cur.children[key]
+		if !ok {
+			child = newNode(segment)
+			child.isParam = isParam
+			cur.children[key] = child
+		}
+		cur = child
+	}
+	cur.handler = handler
+}
+
+func (n *node) search(segments []string) (http.HandlerFunc, Params) {
+	cur := n
+	var params Params
+	for _, segment := range segments {
+		if child, ok := cur.children[segment]; ok {
+			cur = child
+			continue
+		}
+		child, ok := cur.children[":"]
+		if !ok {
+			return nil, nil
+		}
+		params = append(params, Param{Key: strings.TrimPrefix(child.part, ":"), Value: segment})
+		cur = child
+	}
+	return cur.handler, params
+}
+
+// Router is a minimal trie-based HTTP router. It dispatches on method and
+// path, supports named path parameters ("/users/:id"), and makes them
+// available to handlers via ParamsFromContext. It replaces the old
+// regexp-per-route dispatch so new endpoints don't need a hand-written
+// regexp and switch case.
+type Router struct {
+	trees map[string]*node
+}
+
+// NewRouter returns an empty Router ready for route registration.
+func NewRouter() *Router {
+	return &Router{trees: make(map[string]*node)}
+}
+
+// Handle registers handler for method and path. path segments prefixed
+// with ':' are captured as named parameters.
+func (ro *Router) Handle(method, path string, handler http.HandlerFunc) {
+	root, ok := ro.trees[method]
+	if !ok {
+		root = newNode("/")
+		ro.trees[method] = root
+	}
+	root.insert(splitPath(path), handler)
+}
+
+func (ro *Router) GET(path string, handler http.HandlerFunc) {
+	ro.Handle(http.MethodGet, path, handler)
+}
+
+func (ro *Router) POST(path string, handler http.HandlerFunc) {
+	ro.Handle(http.MethodPost, path, handler)
+}
+
+func (ro *Router) PUT(path string, handler http.HandlerFunc) {
+	ro.Handle(http.MethodPut, path, handler)
+}
+
+func (ro *Router) PATCH(path string, handler http.HandlerFunc) {
+	ro.Handle(http.MethodPatch, path, handler)
+}
+
+func (ro *Router) DELETE(path string, handler http.HandlerFunc) {
+	ro.Handle(http.MethodDelete, path, handler)
+}
+
+func (ro *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	root, ok := ro.trees[r.Method]
+	if !ok {
+		notFound(w, r)
+		return
+	}
+	handler, params := root.search(splitPath(r.URL.Path))
+	if handler == nil {
+		notFound(w, r)
+		return
+	}
+	if len(params) > 0 {
+		r = r.WithContext(context.WithValue(r.Context(), paramsKey{}, params))
+	}
+	handler(w, r)
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
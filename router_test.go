@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouterDispatchesByMethodAndPath(t *testing.T) {
+	ro := NewRouter()
+	ro.GET("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("list"))
+	})
+	ro.POST("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("create"))
+	})
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{http.MethodGet, "/users", http.StatusOK, "list"},
+		{http.MethodPost, "/users", http.StatusCreated, "create"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		rec := httptest.NewRecorder()
+		ro.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s %s: got status %d, want %d", tt.method, tt.path, rec.Code, tt.wantCode)
+		}
+		if rec.Body.String() != tt.wantBody {
+			t.Errorf("%s %s: got body %q, want %q", tt.method, tt.path, rec.Body.String(), tt.wantBody)
+		}
+	}
+}
+
+func TestRouterCapturesNamedParams(t *testing.T) {
+	ro := NewRouter()
+	var got Params
+	ro.GET("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		got = ParamsFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	rec := httptest.NewRecorder()
+	ro.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	if id := got.ByName("id"); id != "42" {
+		t.Errorf("got id %q, want %q", id, "42")
+	}
+}
+
+func TestRouterReturnsNotFoundForUnmatchedRoute(t *testing.T) {
+	ro := NewRouter()
+	ro.GET("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	rec := httptest.NewRecorder()
+	ro.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/users", nil)
+	rec = httptest.NewRecorder()
+	ro.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unregistered method: got status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,7 @@
+package users
+
+// Credentials is the payload expected by POST /login.
+type Credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
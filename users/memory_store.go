@@ -0,0 +1,87 @@
+package users
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MemoryStore is a Store backed by a map guarded by an RWMutex. It's the
+// default backend, handy for local development and tests.
+type MemoryStore struct {
+	mu sync.RWMutex
+	m  map[string]User
+}
+
+// NewMemoryStore returns a MemoryStore seeded with a copy of seed.
+func NewMemoryStore(seed map[string]User) *MemoryStore {
+	m := make(map[string]User, len(seed))
+	for id, u := range seed {
+		m[id] = u
+	}
+	return &MemoryStore{m: m}
+}
+
+func (s *MemoryStore) List(ctx context.Context) ([]User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]User, 0, len(s.m))
+	for _, u := range s.m {
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	u, ok := s.m[id]
+	if !ok {
+		return User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+func (s *MemoryStore) Add(ctx context.Context, u User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[u.ID] = u
+	return nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, id string, u User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.m[id]; !ok {
+		return ErrNotFound
+	}
+	s.m[id] = u
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id string) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.m[id]
+	if !ok {
+		return User{}, ErrNotFound
+	}
+	delete(s.m, id)
+	return u, nil
+}
+
+func (s *MemoryStore) FindByCredentials(ctx context.Context, creds Credentials) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, u := range s.m {
+		if u.Email != creds.Email {
+			continue
+		}
+		if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(creds.Password)) != nil {
+			return User{}, ErrInvalidCredentials
+		}
+		return u, nil
+	}
+	return User{}, ErrInvalidCredentials
+}
@@ -0,0 +1,114 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PostgresStore is a Store backed by a "users" table via pgxpool.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore opens a pool using the PGUSER, PASS, HOST, PORT, and
+// DATABASE env vars. PGUSER is deliberately not named USER: that var is
+// set by the login shell to the OS username on essentially every host,
+// which would silently point this at the wrong database user instead of
+// failing loudly.
+func NewPostgresStore(ctx context.Context) (*PostgresStore, error) {
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s",
+		os.Getenv("PGUSER"), os.Getenv("PASS"), os.Getenv("HOST"), os.Getenv("PORT"), os.Getenv("DATABASE"))
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresStore{pool: pool}, nil
+}
+
+func (s *PostgresStore) List(ctx context.Context) ([]User, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, name, email, role, password_hash FROM users`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]User, 0)
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.PasswordHash); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (s *PostgresStore) Get(ctx context.Context, id string) (User, error) {
+	var u User
+	err := s.pool.QueryRow(ctx, `SELECT id, name, email, role, password_hash FROM users WHERE id = $1`, id).
+		Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.PasswordHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, ErrNotFound
+	}
+	if err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
+
+func (s *PostgresStore) Add(ctx context.Context, u User) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO users (id, name, email, role, password_hash)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET name = $2, email = $3, role = $4, password_hash = $5`,
+		u.ID, u.Name, u.Email, u.Role, u.PasswordHash)
+	return err
+}
+
+func (s *PostgresStore) Update(ctx context.Context, id string, u User) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE users SET id = $1, name = $2, email = $3, role = $4, password_hash = $5 WHERE id = $6`,
+		u.ID, u.Name, u.Email, u.Role, u.PasswordHash, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, id string) (User, error) {
+	var u User
+	err := s.pool.QueryRow(ctx, `DELETE FROM users WHERE id = $1 RETURNING id, name, email, role, password_hash`, id).
+		Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.PasswordHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, ErrNotFound
+	}
+	if err != nil {
+		return User{}, err
+	}
+	return u, nil
+}
+
+func (s *PostgresStore) FindByCredentials(ctx context.Context, creds Credentials) (User, error) {
+	var u User
+	err := s.pool.QueryRow(ctx, `SELECT id, name, email, role, password_hash FROM users WHERE email = $1`, creds.Email).
+		Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.PasswordHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(creds.Password)) != nil {
+		return User{}, ErrInvalidCredentials
+	}
+	return u, nil
+}
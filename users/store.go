@@ -0,0 +1,26 @@
+package users
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Store methods when the requested id has no
+// matching record.
+var ErrNotFound = errors.New("user not found")
+
+// ErrInvalidCredentials is returned by FindByCredentials when the email
+// is unknown or the password doesn't match.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Store is the persistence boundary for User records. It's implemented
+// by MemoryStore for local development and PostgresStore for real
+// deployments, selected in main via the STORAGE env var.
+type Store interface {
+	List(ctx context.Context) ([]User, error)
+	Get(ctx context.Context, id string) (User, error)
+	Add(ctx context.Context, u User) error
+	Update(ctx context.Context, id string, u User) error
+	Delete(ctx context.Context, id string) (User, error)
+	FindByCredentials(ctx context.Context, creds Credentials) (User, error)
+}
@@ -0,0 +1,12 @@
+// Package users holds the gocafe user domain model and its storage
+// backends, independent of the HTTP layer in package main.
+package users
+
+// User is the domain record for a gocafe account.
+type User struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email,omitempty"`
+	Role         string `json:"role,omitempty"`
+	PasswordHash string `json:"-" xml:"-"`
+}